@@ -16,11 +16,13 @@ func pgServiceKeyToStructMember(
 ) (string, error) {
 	switch strings.ToLower(key) {
 	case "host":
-		return "Host", nil
+		return "Hosts", nil
 	case "hostaddr":
-		return "HostAddr", nil
+		return "HostAddrs", nil
 	case "port":
-		return "Port", nil
+		return "Ports", nil
+	case "target_session_attrs":
+		return "TargetSessionAttrs", nil
 	case "dbname":
 		return "DBName", nil
 	case "user":
@@ -49,6 +51,8 @@ func pgServiceKeyToStructMember(
 		return "TTY", nil
 	case "sslmode":
 		return "SSLMode", nil
+	case "requiressl":
+		return "RequireSSL", nil
 	case "sslcompression":
 		return "SSLCompression", nil
 	case "sslkey":
@@ -69,3 +73,69 @@ func pgServiceKeyToStructMember(
 
 	return "", fmt.Errorf("Invalid postgres service key value %s", key)
 }
+
+// pgServiceStructMemberToKey is the reverse of pgServiceKeyToStructMember,
+// used by WriteTo to turn a postgresService field name back into the
+// pg_service.conf key it came from.
+func pgServiceStructMemberToKey(
+	member string,
+) (string, error) {
+	switch member {
+	case "Hosts":
+		return "host", nil
+	case "HostAddrs":
+		return "hostaddr", nil
+	case "Ports":
+		return "port", nil
+	case "TargetSessionAttrs":
+		return "target_session_attrs", nil
+	case "DBName":
+		return "dbname", nil
+	case "User":
+		return "user", nil
+	case "Password":
+		return "password", nil
+	case "ConnectTimeout":
+		return "connect_timeout", nil
+	case "ClientEncoding":
+		return "client_encoding", nil
+	case "Options":
+		return "options", nil
+	case "ApplicationName":
+		return "application_name", nil
+	case "FallbackApplicationName":
+		return "fallback_application_name", nil
+	case "KeepAlives":
+		return "keepalives", nil
+	case "KeepAlivesIdle":
+		return "keepalives_idle", nil
+	case "KeepAlivesInterval":
+		return "keepalives_interval", nil
+	case "KeepAlivesCount":
+		return "keepalives_count", nil
+	case "TTY":
+		return "tty", nil
+	case "SSLMode":
+		return "sslmode", nil
+	case "RequireSSL":
+		return "requiressl", nil
+	case "SSLCompression":
+		return "sslcompression", nil
+	case "SSLKey":
+		return "sslkey", nil
+	case "SSLRootCert":
+		return "sslrootcert", nil
+	case "SSLCrl":
+		return "sslcrl", nil
+	case "RequirePeer":
+		return "requirepeer", nil
+	case "KrbSrvname":
+		return "krbsrvname", nil
+	case "GSSLib":
+		return "gsslib", nil
+	case "SSLCert":
+		return "sslcert", nil
+	}
+
+	return "", fmt.Errorf("Invalid postgres service struct member %s", member)
+}
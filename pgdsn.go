@@ -0,0 +1,201 @@
+package pgservices
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// dsnKeyOrder controls the order KeywordValueDSN emits keys in. It's not
+// required by libpq (key=value pairs may appear in any order) but a fixed
+// order makes the output stable and easy to diff.
+var dsnKeyOrder = []string{
+	"host",
+	"hostaddr",
+	"port",
+	"dbname",
+	"user",
+	"password",
+	"connect_timeout",
+	"client_encoding",
+	"options",
+	"application_name",
+	"fallback_application_name",
+	"keepalives",
+	"keepalives_idle",
+	"keepalives_interval",
+	"keepalives_count",
+	"sslmode",
+	"requiressl",
+	"sslcompression",
+	"sslcert",
+	"sslkey",
+	"sslrootcert",
+	"sslcrl",
+	"requirepeer",
+	"krbsrvname",
+	"gsslib",
+	"target_session_attrs",
+}
+
+// quoteDSNValue quotes a libpq keyword/value pair's value if it's empty or
+// contains whitespace, a single quote or a backslash, escaping backslashes
+// and single quotes as it goes. Unquoted values don't need any escaping.
+func quoteDSNValue(value string) string {
+	if value == "" {
+		return "''"
+	}
+
+	needsQuoting := strings.ContainsAny(value, " \t\\'")
+	if !needsQuoting {
+		return value
+	}
+
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range value {
+		if r == '\\' || r == '\'' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('\'')
+
+	return b.String()
+}
+
+// keywordValues returns the non-default key/value pairs for p in DSN order,
+// shared by KeywordValueDSN and URL so both stay in sync. tty and keywords
+// held only in registryExtra (see Extra) are not included.
+func (p postgresService) keywordValues() []string {
+	m := map[string]string{
+		"host":                      strings.Join(p.Hosts, ","),
+		"dbname":                    p.DBName,
+		"user":                      p.User,
+		"password":                  p.Password,
+		"client_encoding":           p.ClientEncoding,
+		"options":                   p.Options,
+		"application_name":          p.ApplicationName,
+		"fallback_application_name": p.FallbackApplicationName,
+		"sslmode":                   p.SSLMode,
+		"sslcert":                   p.SSLCert,
+		"sslkey":                    p.SSLKey,
+		"sslrootcert":               p.SSLRootCert,
+		"sslcrl":                    p.SSLCrl,
+		"requirepeer":               p.RequirePeer,
+		"krbsrvname":                p.KrbSrvname,
+		"gsslib":                    p.GSSLib,
+	}
+
+	if len(p.HostAddrs) > 0 {
+		addrStrs := make([]string, len(p.HostAddrs))
+		for i, addr := range p.HostAddrs {
+			addrStrs[i] = addr.String()
+		}
+		m["hostaddr"] = strings.Join(addrStrs, ",")
+	}
+	if len(p.Ports) > 0 {
+		portStrs := make([]string, len(p.Ports))
+		for i, port := range p.Ports {
+			portStrs[i] = strconv.Itoa(port)
+		}
+		m["port"] = strings.Join(portStrs, ",")
+	}
+	if p.TargetSessionAttrs != "" {
+		m["target_session_attrs"] = string(p.TargetSessionAttrs)
+	}
+	if p.ConnectTimeout != 0 {
+		m["connect_timeout"] = strconv.Itoa(p.ConnectTimeout)
+	}
+	if p.KeepAlives {
+		m["keepalives"] = "1"
+	}
+	if p.KeepAlivesIdle != 0 {
+		m["keepalives_idle"] = strconv.Itoa(p.KeepAlivesIdle)
+	}
+	if p.KeepAlivesInterval != 0 {
+		m["keepalives_interval"] = strconv.Itoa(p.KeepAlivesInterval)
+	}
+	if p.KeepAlivesCount != 0 {
+		m["keepalives_count"] = strconv.Itoa(p.KeepAlivesCount)
+	}
+	if p.RequireSSL {
+		m["requiressl"] = "1"
+	}
+	if p.SSLCompression {
+		m["sslcompression"] = "1"
+	}
+
+	pairs := make([]string, 0, len(dsnKeyOrder))
+	for _, key := range dsnKeyOrder {
+		if v, ok := m[key]; ok && v != "" {
+			pairs = append(pairs, key+"="+v)
+		}
+	}
+
+	return pairs
+}
+
+// KeywordValueDSN renders p as a libpq keyword/value connection string
+// (`host=localhost port=5432 dbname=test ...`), quoting any value that
+// contains whitespace or a quote/backslash character the way libpq's
+// PQconninfoParse expects.
+func (p postgresService) KeywordValueDSN() string {
+	pairs := p.keywordValues()
+	quoted := make([]string, len(pairs))
+
+	for i, pair := range pairs {
+		key, value, _ := strings.Cut(pair, "=")
+		quoted[i] = key + "=" + quoteDSNValue(value)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// URL renders p as a postgresql:// connection URI. User, password, host,
+// port and dbname map onto the URI's userinfo/host/path components; every
+// other keyword keywordValues emits is carried in the query string. tty and
+// keywords held only in registryExtra are left out.
+func (p postgresService) URL() (*url.URL, error) {
+	u := &url.URL{
+		Scheme: "postgresql",
+		Path:   "/" + p.DBName,
+	}
+
+	if p.User != "" {
+		if p.Password != "" {
+			u.User = url.UserPassword(p.User, p.Password)
+		} else {
+			u.User = url.User(p.User)
+		}
+	}
+
+	endpoints, err := p.Fallbacks()
+	if err != nil {
+		return nil, err
+	}
+
+	hostParts := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		if endpoint.Port != 0 {
+			hostParts[i] = fmt.Sprintf("%s:%d", endpoint.Host, endpoint.Port)
+		} else {
+			hostParts[i] = endpoint.Host
+		}
+	}
+	u.Host = strings.Join(hostParts, ",")
+
+	query := url.Values{}
+	for _, pair := range p.keywordValues() {
+		key, value, _ := strings.Cut(pair, "=")
+		switch key {
+		case "host", "port", "dbname", "user", "password":
+			continue
+		}
+		query.Set(key, value)
+	}
+	u.RawQuery = query.Encode()
+
+	return u, nil
+}
@@ -0,0 +1,92 @@
+package pgservices
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+// TestKeywordValueDSNRoundTrip checks that a service parsed out of a
+// pg_service.conf buffer can be rendered back to a DSN and re-parsed (via
+// ini's own key=value handling) without losing any of its fields.
+func TestKeywordValueDSNRoundTrip(t *testing.T) {
+	buffer := `[service_one]
+host = db.example.com
+port = 5432
+dbname = test_db
+user = dbuser
+password = abc123
+sslmode = require
+application_name = myapp
+`
+
+	byteReadCloser := ioutil.NopCloser(bytes.NewReader([]byte(buffer)))
+	services, err := ParsePgServices(byteReadCloser)
+	if err != nil {
+		t.Fatalf("Error parsing buffer: %v", err)
+	}
+
+	svc := services.Category["service_one"]
+	dsn := svc.KeywordValueDSN()
+
+	reparsed, err := ParsePgServices(ioutil.NopCloser(bytes.NewReader(
+		[]byte("[service_one]\n" + dsnToIni(dsn)),
+	)))
+	if err != nil {
+		t.Fatalf("Error reparsing DSN %q: %v", dsn, err)
+	}
+
+	roundTripped := reparsed.Category["service_one"]
+	if !reflect.DeepEqual(roundTripped, svc) {
+		t.Errorf("Round-tripped service doesn't match original.\noriginal: %#v\nroundtrip: %#v", svc, roundTripped)
+	}
+}
+
+// dsnToIni turns a libpq `key=value key=value` DSN into newline-separated
+// `key = value` lines, which is the only translation needed for ini.v1 to
+// parse it back as a section body.
+func dsnToIni(dsn string) string {
+	out := ""
+	key := ""
+	value := ""
+	inValue := false
+	inQuotes := false
+
+	flush := func() {
+		if key != "" {
+			out += key + " = " + value + "\n"
+		}
+		key = ""
+		value = ""
+		inValue = false
+	}
+
+	for i := 0; i < len(dsn); i++ {
+		c := dsn[i]
+		switch {
+		case !inValue && c == '=':
+			inValue = true
+		case !inValue && c == ' ':
+			// between pairs, nothing to do
+		case !inValue:
+			key += string(c)
+		case inValue && c == '\'' && value == "":
+			inQuotes = true
+		case inValue && c == '\'' && inQuotes:
+			inQuotes = false
+		case inValue && c == '\\' && inQuotes:
+			i++
+			if i < len(dsn) {
+				value += string(dsn[i])
+			}
+		case inValue && c == ' ' && !inQuotes:
+			flush()
+		case inValue:
+			value += string(c)
+		}
+	}
+	flush()
+
+	return out
+}
@@ -0,0 +1,70 @@
+package pgservices
+
+import "fmt"
+
+// TargetSessionAttrsMode is the value of a service's target_session_attrs
+// keyword, used to pick which fallback endpoint in a multi-host connection
+// string a client should end up connected to.
+type TargetSessionAttrsMode string
+
+// The full set of target_session_attrs values libpq/pgx accept.
+const (
+	TargetSessionAttrsAny           TargetSessionAttrsMode = "any"
+	TargetSessionAttrsReadWrite     TargetSessionAttrsMode = "read-write"
+	TargetSessionAttrsReadOnly      TargetSessionAttrsMode = "read-only"
+	TargetSessionAttrsPrimary       TargetSessionAttrsMode = "primary"
+	TargetSessionAttrsStandby       TargetSessionAttrsMode = "standby"
+	TargetSessionAttrsPreferStandby TargetSessionAttrsMode = "prefer-standby"
+)
+
+// TargetSessionAttrsModes lists every valid TargetSessionAttrsMode, used by
+// Set to validate a target_session_attrs value parsed out of a service file.
+var TargetSessionAttrsModes = []TargetSessionAttrsMode{
+	TargetSessionAttrsAny,
+	TargetSessionAttrsReadWrite,
+	TargetSessionAttrsReadOnly,
+	TargetSessionAttrsPrimary,
+	TargetSessionAttrsStandby,
+	TargetSessionAttrsPreferStandby,
+}
+
+// FallbackEndpoint is a single host/port pair to try when connecting,
+// expanded out of a service's (possibly multi-valued) Hosts/Ports.
+type FallbackEndpoint struct {
+	Host string
+	Port int
+}
+
+// Fallbacks expands p's Hosts/Ports into the ordered list of endpoints a
+// connect loop should try in turn, matching pgx's Config.Fallbacks model. A
+// single port applies to every host; otherwise Hosts and Ports must be the
+// same length and are paired up positionally.
+func (p postgresService) Fallbacks() ([]FallbackEndpoint, error) {
+	if len(p.Hosts) == 0 {
+		return nil, nil
+	}
+
+	ports := p.Ports
+	if len(ports) == 0 {
+		ports = []int{0}
+	}
+
+	if len(ports) != 1 && len(ports) != len(p.Hosts) {
+		return nil, fmt.Errorf(
+			"Cannot pair %d hosts with %d ports: counts must match or there must be exactly one port",
+			len(p.Hosts), len(ports),
+		)
+	}
+
+	endpoints := make([]FallbackEndpoint, len(p.Hosts))
+	for i, host := range p.Hosts {
+		port := ports[0]
+		if len(ports) > 1 {
+			port = ports[i]
+		}
+
+		endpoints[i] = FallbackEndpoint{Host: host, Port: port}
+	}
+
+	return endpoints, nil
+}
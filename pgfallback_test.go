@@ -0,0 +1,120 @@
+package pgservices
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestFallbacksSinglePortManyHosts(t *testing.T) {
+	svc := postgresService{Hosts: []string{"h1", "h2", "h3"}, Ports: []int{5432}}
+
+	endpoints, err := svc.Fallbacks()
+	if err != nil {
+		t.Fatalf("Error expanding fallbacks: %v", err)
+	}
+
+	expected := []FallbackEndpoint{
+		{Host: "h1", Port: 5432},
+		{Host: "h2", Port: 5432},
+		{Host: "h3", Port: 5432},
+	}
+
+	if len(endpoints) != len(expected) {
+		t.Fatalf("Expected %d endpoints, got %d: %v", len(expected), len(endpoints), endpoints)
+	}
+	for i := range expected {
+		if endpoints[i] != expected[i] {
+			t.Errorf("endpoint %d: expected %+v, got %+v", i, expected[i], endpoints[i])
+		}
+	}
+}
+
+func TestFallbacksPositionalPairing(t *testing.T) {
+	svc := postgresService{Hosts: []string{"h1", "h2"}, Ports: []int{5432, 5433}}
+
+	endpoints, err := svc.Fallbacks()
+	if err != nil {
+		t.Fatalf("Error expanding fallbacks: %v", err)
+	}
+
+	expected := []FallbackEndpoint{
+		{Host: "h1", Port: 5432},
+		{Host: "h2", Port: 5433},
+	}
+
+	if len(endpoints) != len(expected) {
+		t.Fatalf("Expected %d endpoints, got %d: %v", len(expected), len(endpoints), endpoints)
+	}
+	for i := range expected {
+		if endpoints[i] != expected[i] {
+			t.Errorf("endpoint %d: expected %+v, got %+v", i, expected[i], endpoints[i])
+		}
+	}
+}
+
+func TestFallbacksHostPortCountMismatch(t *testing.T) {
+	svc := postgresService{Hosts: []string{"h1", "h2", "h3"}, Ports: []int{5432, 5433}}
+
+	if _, err := svc.Fallbacks(); err == nil {
+		t.Errorf("Expected an error when host count and port count disagree and port count isn't 1")
+	}
+}
+
+func TestFallbacksNoHosts(t *testing.T) {
+	svc := postgresService{}
+
+	endpoints, err := svc.Fallbacks()
+	if err != nil {
+		t.Fatalf("Error expanding fallbacks: %v", err)
+	}
+	if endpoints != nil {
+		t.Errorf("Expected no endpoints for a service with no hosts, got %v", endpoints)
+	}
+}
+
+// TestSetParsesMultiHostPortLists checks that ParsePgServices (via Set)
+// turns comma-separated host/port lists into the Hosts/Ports slices
+// Fallbacks relies on.
+func TestSetParsesMultiHostPortLists(t *testing.T) {
+	buffer := `[service_one]
+host = h1,h2,h3
+port = 5432,5433,5434
+dbname = test_db
+`
+
+	services, err := ParsePgServices(ioutil.NopCloser(bytes.NewReader([]byte(buffer))))
+	if err != nil {
+		t.Fatalf("Error parsing buffer: %v", err)
+	}
+
+	svc := services.Category["service_one"]
+
+	expectedHosts := []string{"h1", "h2", "h3"}
+	if len(svc.Hosts) != len(expectedHosts) {
+		t.Fatalf("Expected %d hosts, got %v", len(expectedHosts), svc.Hosts)
+	}
+	for i, host := range expectedHosts {
+		if svc.Hosts[i] != host {
+			t.Errorf("host %d: expected %q, got %q", i, host, svc.Hosts[i])
+		}
+	}
+
+	expectedPorts := []int{5432, 5433, 5434}
+	if len(svc.Ports) != len(expectedPorts) {
+		t.Fatalf("Expected %d ports, got %v", len(expectedPorts), svc.Ports)
+	}
+	for i, port := range expectedPorts {
+		if svc.Ports[i] != port {
+			t.Errorf("port %d: expected %d, got %d", i, port, svc.Ports[i])
+		}
+	}
+
+	endpoints, err := svc.Fallbacks()
+	if err != nil {
+		t.Fatalf("Error expanding fallbacks: %v", err)
+	}
+	if len(endpoints) != 3 {
+		t.Errorf("Expected 3 fallback endpoints, got %d: %v", len(endpoints), endpoints)
+	}
+}
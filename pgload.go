@@ -0,0 +1,87 @@
+package pgservices
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// defaultSystemServiceFile is the compiled-in fallback location for
+// pg_service.conf, matching libpq's default sysconfdir install location.
+const defaultSystemServiceFile = "/etc/pg_service.conf"
+
+// resolveServiceFilePath figures out which pg_service.conf to use, following
+// the same precedence libpq (and pgx's pgservicefile package) use:
+//
+//  1. $PGSERVICEFILE
+//  2. $PGSYSCONFDIR/pg_service.conf
+//  3. ~/.pg_service.conf
+//  4. the compiled-in system default
+func resolveServiceFilePath() (string, error) {
+	if path := os.Getenv("PGSERVICEFILE"); path != "" {
+		return path, nil
+	}
+
+	if sysconfdir := os.Getenv("PGSYSCONFDIR"); sysconfdir != "" {
+		return filepath.Join(sysconfdir, "pg_service.conf"), nil
+	}
+
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		candidate := filepath.Join(home, ".pg_service.conf")
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return defaultSystemServiceFile, nil
+}
+
+// Load discovers a pg_service.conf file using the same search order as
+// libpq/pgx and parses it. It returns the parsed PostgresServiceGroup along
+// with the path that was actually used, so callers can report where their
+// configuration came from.
+func Load() (*PostgresServiceGroup, string, error) {
+	path, err := resolveServiceFilePath()
+	if err != nil {
+		return nil, "", err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+
+	services, err := ParsePgServices(f)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return services, path, nil
+}
+
+// LoadNamed discovers and parses pg_service.conf via Load, then returns the
+// single service named by name. If name is empty, $PGSERVICE is used
+// instead, matching libpq's behavior of picking up the active service from
+// the environment when the caller doesn't specify one explicitly.
+func LoadNamed(name string) (*PostgresService, error) {
+	if name == "" {
+		name = os.Getenv("PGSERVICE")
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("No service name provided and $PGSERVICE is not set")
+	}
+
+	services, path, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	svc, ok := services.Category[name]
+	if !ok {
+		return nil, fmt.Errorf("No service named `%s' found in %s", name, path)
+	}
+
+	return &svc, nil
+}
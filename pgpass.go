@@ -0,0 +1,149 @@
+package pgservices
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// defaultPgpassFilename is the dotfile libpq falls back to under the user's
+// home directory when $PGPASSFILE isn't set.
+const defaultPgpassFilename = ".pgpass"
+
+// DefaultPgpassPath resolves the .pgpass file libpq/pgx would use: first
+// $PGPASSFILE, then ~/.pgpass.
+func DefaultPgpassPath() (string, error) {
+	if path := os.Getenv("PGPASSFILE"); path != "" {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, defaultPgpassFilename), nil
+}
+
+// unescapePgpassField undoes the `\:` and `\\` escaping libpq uses so a
+// hostname, database or username can itself contain a colon.
+func unescapePgpassField(field string) string {
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+1 < len(field) {
+			i++
+		}
+		b.WriteByte(field[i])
+	}
+	return b.String()
+}
+
+// splitPgpassLine splits a .pgpass line into its five colon-separated
+// fields, honoring `\:` as an escaped, non-delimiting colon.
+func splitPgpassLine(line string) []string {
+	var fields []string
+	var cur strings.Builder
+
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line):
+			cur.WriteByte(line[i])
+			cur.WriteByte(line[i+1])
+			i++
+		case line[i] == ':':
+			fields = append(fields, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(line[i])
+		}
+	}
+	fields = append(fields, cur.String())
+
+	return fields
+}
+
+// pgpassFieldMatches reports whether a .pgpass field matches value, where
+// "*" matches anything, per libpq's pgpass rules.
+func pgpassFieldMatches(field, value string) bool {
+	return field == "*" || unescapePgpassField(field) == value
+}
+
+// checkPgpassPermissions mirrors libpq's refusal to use a .pgpass file
+// that's readable by anyone other than its owner, since it holds plaintext
+// passwords.
+func checkPgpassPermissions(path string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("Password file \"%s\" has group or world access; permissions should be u=rw (0600) or less", path)
+	}
+
+	return nil
+}
+
+// ResolvePassword looks up a password for p in a libpq-format .pgpass file
+// (hostname:port:database:username:password, one entry per line, `*`
+// wildcards, `\:`/`\\` escapes). It's meant to be called when p.Password is
+// empty, matching how libpq/pgx fall back to .pgpass.
+func (p postgresService) ResolvePassword(pgpassPath string) (string, error) {
+	if err := checkPgpassPermissions(pgpassPath); err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(pgpassPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hosts := p.Hosts
+	if len(hosts) == 0 {
+		hosts = []string{"localhost"}
+	}
+
+	ports := p.Ports
+	if len(ports) == 0 {
+		ports = []int{5432}
+	}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := splitPgpassLine(line)
+		if len(fields) != 5 {
+			continue
+		}
+
+		for _, host := range hosts {
+			for _, port := range ports {
+				if pgpassFieldMatches(fields[0], host) &&
+					pgpassFieldMatches(fields[1], strconv.Itoa(port)) &&
+					pgpassFieldMatches(fields[2], p.DBName) &&
+					pgpassFieldMatches(fields[3], p.User) {
+					return unescapePgpassField(fields[4]), nil
+				}
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("No matching entry found in %s", pgpassPath)
+}
@@ -0,0 +1,90 @@
+package pgservices
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// writePgpassFile is a small test helper that writes contents to a fresh
+// file under t.TempDir() with the given permissions and returns its path.
+func writePgpassFile(t *testing.T, contents string, perm os.FileMode) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".pgpass")
+	if err := os.WriteFile(path, []byte(contents), perm); err != nil {
+		t.Fatalf("Error writing test .pgpass file: %v", err)
+	}
+
+	return path
+}
+
+func TestResolvePasswordRejectsGroupOrWorldReadable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits aren't enforced the same way on windows")
+	}
+
+	path := writePgpassFile(t, "localhost:5432:mydb:myuser:secret\n", 0644)
+
+	svc := postgresService{Hosts: []string{"localhost"}, Ports: []int{5432}, DBName: "mydb", User: "myuser"}
+	_, err := svc.ResolvePassword(path)
+	if err == nil {
+		t.Fatalf("Expected a group/world-readable .pgpass file to be rejected")
+	}
+}
+
+func TestResolvePasswordEscapedColon(t *testing.T) {
+	path := writePgpassFile(t, `db\:host:5432:mydb:myuser:secret
+`, 0600)
+
+	svc := postgresService{Hosts: []string{"db:host"}, Ports: []int{5432}, DBName: "mydb", User: "myuser"}
+	password, err := svc.ResolvePassword(path)
+	if err != nil {
+		t.Fatalf("Error resolving password: %v", err)
+	}
+	if password != "secret" {
+		t.Errorf("Expected password %q, got %q", "secret", password)
+	}
+}
+
+func TestResolvePasswordWildcard(t *testing.T) {
+	path := writePgpassFile(t, "*:*:*:*:wildcardpass\n", 0600)
+
+	svc := postgresService{Hosts: []string{"anyhost"}, Ports: []int{1234}, DBName: "anydb", User: "anyuser"}
+	password, err := svc.ResolvePassword(path)
+	if err != nil {
+		t.Fatalf("Error resolving password: %v", err)
+	}
+	if password != "wildcardpass" {
+		t.Errorf("Expected password %q, got %q", "wildcardpass", password)
+	}
+}
+
+// TestResolvePasswordMultiHostFileOrder documents ResolvePassword's actual
+// search order for a multi-host service: it scans the .pgpass file line by
+// line and, for each line, checks every host in Hosts, so an earlier line
+// matching a later host wins over a later line matching the first host.
+func TestResolvePasswordMultiHostFileOrder(t *testing.T) {
+	path := writePgpassFile(t, `host2:5432:mydb:myuser:passforhost2
+host1:5432:mydb:myuser:passforhost1
+`, 0600)
+
+	svc := postgresService{Hosts: []string{"host1", "host2"}, Ports: []int{5432}, DBName: "mydb", User: "myuser"}
+	password, err := svc.ResolvePassword(path)
+	if err != nil {
+		t.Fatalf("Error resolving password: %v", err)
+	}
+	if password != "passforhost2" {
+		t.Errorf("Expected the earlier matching line (host2) to win, got %q", password)
+	}
+}
+
+func TestResolvePasswordNoMatch(t *testing.T) {
+	path := writePgpassFile(t, "otherhost:5432:mydb:myuser:secret\n", 0600)
+
+	svc := postgresService{Hosts: []string{"localhost"}, Ports: []int{5432}, DBName: "mydb", User: "myuser"}
+	if _, err := svc.ResolvePassword(path); err == nil {
+		t.Errorf("Expected an error when no .pgpass entry matches")
+	}
+}
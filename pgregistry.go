@@ -0,0 +1,156 @@
+package pgservices
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// KeywordKind describes the Go type a Keyword's value is stored/coerced as
+// in a postgresService's registryExtra map.
+type KeywordKind int
+
+// The set of value types a registered Keyword can hold.
+const (
+	KeywordString KeywordKind = iota
+	KeywordInt
+	KeywordBool
+	KeywordEnum
+)
+
+// KeywordValidator is run against the raw string value parsed out of
+// pg_service.conf before it's coerced according to the Keyword's Kind.
+// Returning an error rejects the assignment.
+type KeywordValidator func(value string) error
+
+// Keyword describes a single libpq-style connection parameter: its
+// canonical name, any aliases it's also known by, the type it's stored as,
+// an optional extra validator and a Default value. Default is only stored
+// with the Keyword: neither ParsePgServices nor Extra fills it in for a
+// service that leaves the keyword unset. EnumValues only applies when Kind
+// is KeywordEnum.
+type Keyword struct {
+	Name       string
+	Aliases    []string
+	Kind       KeywordKind
+	EnumValues []string
+	Validator  KeywordValidator
+	Default    any
+}
+
+// keywordRegistry holds every Keyword known to the package, indexed by
+// lower-cased name and alias.
+var keywordRegistry = map[string]*Keyword{}
+
+// RegisterKeyword teaches the package about a new libpq keyword, or
+// replaces an existing one. Downstream users call this to add support for
+// options this package doesn't ship with yet, such as ones added in newer
+// PostgreSQL releases, without needing to edit pgservices itself.
+func RegisterKeyword(kw Keyword) {
+	keywordRegistry[strings.ToLower(kw.Name)] = &kw
+	for _, alias := range kw.Aliases {
+		keywordRegistry[strings.ToLower(alias)] = &kw
+	}
+}
+
+// LookupKeyword resolves a pg_service.conf key (canonical name or alias) to
+// its registered Keyword, if any.
+func LookupKeyword(key string) (Keyword, bool) {
+	kw, ok := keywordRegistry[strings.ToLower(key)]
+	if !ok {
+		return Keyword{}, false
+	}
+	return *kw, true
+}
+
+func init() {
+	for _, kw := range defaultKeywords {
+		RegisterKeyword(kw)
+	}
+}
+
+// defaultKeywords is the full set of libpq keywords this package ships
+// with, including several pgServiceKeyToStructMember doesn't map onto the
+// fixed postgresService struct yet (channel_binding, sslpassword, sslsni,
+// gssencmode, load_balance_hosts, service, passfile).
+// They're registered here so ParsePgServices can accept them into
+// registryExtra even before postgresService grows dedicated fields for
+// them.
+var defaultKeywords = []Keyword{
+	{Name: "host", Kind: KeywordString},
+	{Name: "hostaddr", Kind: KeywordString},
+	{Name: "port", Kind: KeywordInt},
+	{Name: "dbname", Kind: KeywordString},
+	{Name: "user", Kind: KeywordString},
+	{Name: "password", Kind: KeywordString},
+	{Name: "passfile", Kind: KeywordString},
+	{Name: "connect_timeout", Kind: KeywordInt},
+	{Name: "client_encoding", Kind: KeywordString},
+	{Name: "options", Kind: KeywordString},
+	{Name: "application_name", Kind: KeywordString},
+	{Name: "fallback_application_name", Kind: KeywordString},
+	{Name: "keepalives", Kind: KeywordBool},
+	{Name: "keepalives_idle", Kind: KeywordInt},
+	{Name: "keepalives_interval", Kind: KeywordInt},
+	{Name: "keepalives_count", Kind: KeywordInt},
+	{Name: "tty", Kind: KeywordString},
+	{Name: "sslmode", Kind: KeywordEnum, EnumValues: SslModes},
+	{Name: "requiressl", Kind: KeywordBool},
+	{Name: "sslcompression", Kind: KeywordBool},
+	{Name: "sslcert", Kind: KeywordString},
+	{Name: "sslkey", Kind: KeywordString},
+	{Name: "sslrootcert", Kind: KeywordString},
+	{Name: "sslcrl", Kind: KeywordString},
+	{Name: "sslpassword", Kind: KeywordString},
+	{Name: "sslsni", Kind: KeywordBool},
+	{Name: "requirepeer", Kind: KeywordString},
+	{Name: "krbsrvname", Kind: KeywordString},
+	{Name: "gsslib", Kind: KeywordString},
+	{Name: "gssencmode", Kind: KeywordEnum, EnumValues: []string{"disable", "prefer", "require"}},
+	{Name: "channel_binding", Kind: KeywordEnum, EnumValues: []string{"disable", "prefer", "require"}},
+	{
+		Name: "target_session_attrs", Kind: KeywordEnum,
+		EnumValues: []string{"any", "read-write", "read-only", "primary", "standby", "prefer-standby"},
+	},
+	{Name: "load_balance_hosts", Kind: KeywordEnum, EnumValues: []string{"disable", "random"}},
+	{Name: "service", Kind: KeywordString},
+}
+
+// coerceKeywordValue validates value against kw's Validator/Kind/EnumValues
+// and returns it converted to the Go type the keyword is stored as.
+// postgresService.registryExtra (see ParsePgServices and WriteTo) is the
+// actual extension mechanism this registry backs: a key with no dedicated
+// postgresService field is coerced through here and kept in that map
+// instead of being rejected, which is what lets RegisterKeyword teach the
+// parser about a keyword postgresService doesn't have a field for.
+func coerceKeywordValue(kw Keyword, value string) (any, error) {
+	if kw.Validator != nil {
+		if err := kw.Validator(value); err != nil {
+			return nil, err
+		}
+	}
+
+	switch kw.Kind {
+	case KeywordInt:
+		intVal, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+		return intVal, nil
+	case KeywordBool:
+		lowerVal := strings.ToLower(value)
+		if lowerVal == "" {
+			return nil, fmt.Errorf("No value provided for bool type %s", kw.Name)
+		}
+		return lowerVal != "false" && lowerVal != "f" && lowerVal != "0", nil
+	case KeywordEnum:
+		for _, allowed := range kw.EnumValues {
+			if allowed == value {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("Invalid value %q for %s", value, kw.Name)
+	default:
+		return value, nil
+	}
+}
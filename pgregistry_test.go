@@ -0,0 +1,69 @@
+package pgservices
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+// TestRegisterKeywordFlowsIntoParse checks that teaching the package a new
+// keyword via RegisterKeyword actually changes what ParsePgServices accepts,
+// rather than just being recorded in the registry and ignored by the
+// parser.
+func TestRegisterKeywordFlowsIntoParse(t *testing.T) {
+	RegisterKeyword(Keyword{Name: "pg17_test_keyword", Kind: KeywordString})
+
+	buffer := `[service_one]
+host = localhost
+pg17_test_keyword = some_value
+`
+
+	services, err := ParsePgServices(ioutil.NopCloser(bytes.NewReader([]byte(buffer))))
+	if err != nil {
+		t.Fatalf("Error parsing buffer with a registered-but-field-less keyword: %v", err)
+	}
+
+	svc := services.Category["service_one"]
+	v, ok := svc.Extra("pg17_test_keyword")
+	if !ok {
+		t.Fatalf("Expected pg17_test_keyword to be recorded in registryExtra")
+	}
+	if v != "some_value" {
+		t.Errorf("Expected pg17_test_keyword = some_value, got %v", v)
+	}
+}
+
+// TestParseUnregisteredKeywordStillFails checks that a key that's neither a
+// struct field nor a registered keyword is still rejected.
+func TestParseUnregisteredKeywordStillFails(t *testing.T) {
+	buffer := `[service_one]
+host = localhost
+totally_made_up_keyword = wat
+`
+
+	_, err := ParsePgServices(ioutil.NopCloser(bytes.NewReader([]byte(buffer))))
+	if err == nil {
+		t.Errorf("Expected an error parsing an unregistered keyword")
+	}
+}
+
+// TestLookupKeyword checks that LookupKeyword resolves both the default
+// registry's canonical names and values registered at runtime.
+func TestLookupKeyword(t *testing.T) {
+	if _, ok := LookupKeyword("sslmode"); !ok {
+		t.Errorf("Expected sslmode to be registered by default")
+	}
+
+	if _, ok := LookupKeyword("not_a_real_keyword"); ok {
+		t.Errorf("Expected not_a_real_keyword to be unregistered")
+	}
+
+	RegisterKeyword(Keyword{Name: "custom_keyword", Kind: KeywordBool})
+	kw, ok := LookupKeyword("custom_keyword")
+	if !ok {
+		t.Fatalf("Expected custom_keyword to be registered")
+	}
+	if kw.Kind != KeywordBool {
+		t.Errorf("Expected custom_keyword's Kind to be KeywordBool, got %v", kw.Kind)
+	}
+}
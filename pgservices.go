@@ -30,15 +30,24 @@ var format = logging.MustStringFormatter(
 	`%{color}%{time:15:04:05.000} %{shortfunc} ▶ %{level:.4s} %{id:03x}%{color:reset} %{message}`,
 )
 
+// PostgresService is an exported alias for postgresService, letting callers
+// outside the package name the type (for example when building one up to
+// pass to PostgresServiceGroup.AddService) without us giving up the
+// unexported struct internally.
+type PostgresService = postgresService
+
 // postgresService is an individual service definition's
 // (in the context of pg_service.conf) worth of values
 type postgresService struct {
-	DBName   string
-	Host     string
-	HostAddr net.IP
-	Port     int
-	User     string
-	Password string
+	DBName string
+	// Hosts, HostAddrs and Ports are slices because libpq (and pgx) accept
+	// comma-separated lists of each for fallback/HA connect loops. A single
+	// host still just means a one-element slice. See Fallbacks().
+	Hosts     []string
+	HostAddrs []net.IP
+	Ports     []int
+	User      string
+	Password  string
 
 	ConnectTimeout int
 	ClientEncoding string
@@ -67,6 +76,36 @@ type postgresService struct {
 
 	KrbSrvname string
 	GSSLib     string
+
+	// TargetSessionAttrs controls which fallback endpoint (see Fallbacks)
+	// libpq/pgx should settle on, eg. "read-write" to skip standbys.
+	TargetSessionAttrs TargetSessionAttrsMode
+
+	// registryExtra holds values for keys that are known to the keyword
+	// registry (see RegisterKeyword) but don't have a dedicated field above
+	// yet, such as one a caller registers after this struct was written.
+	// It's what keeps postgresService a thin view over the registry instead
+	// of a hard ceiling on which keywords ParsePgServices can accept. Use
+	// Extra to read a value back out.
+	registryExtra map[string]any
+}
+
+// Extra returns the value ParsePgServices stored for a registered keyword
+// that doesn't have a dedicated postgresService field, such as one taught
+// to the parser via RegisterKeyword after this struct was written. ok is
+// false if key isn't registered or was never set on p.
+func (p postgresService) Extra(key string) (any, bool) {
+	if p.registryExtra == nil {
+		return nil, false
+	}
+
+	kw, ok := LookupKeyword(key)
+	if !ok {
+		return nil, false
+	}
+
+	v, ok := p.registryExtra[kw.Name]
+	return v, ok
 }
 
 // PostgresServiceGroup is the object that holds
@@ -108,15 +147,20 @@ func (p postgresService) String() string {
 		sslCompressionInt = 1
 	}
 
-	hostAddrStr := ""
-	if p.HostAddr != nil {
-		hostAddrStr = string(p.HostAddr)
+	hostAddrStrs := make([]string, len(p.HostAddrs))
+	for i, addr := range p.HostAddrs {
+		hostAddrStrs[i] = addr.String()
+	}
+
+	portStrs := make([]string, len(p.Ports))
+	for i, port := range p.Ports {
+		portStrs[i] = strconv.Itoa(port)
 	}
 
 	return fmt.Sprintf(`dbname = %s
 host = %s
 hostaddr = %v
-port = %d
+port = %s
 user = %s
 password = %s
 
@@ -145,11 +189,13 @@ requirepeer = %s
 
 krbsrvname = %s
 gsslib = %s
+
+target_session_attrs = %s
 `,
 		p.DBName,
-		p.Host,
-		hostAddrStr,
-		p.Port,
+		strings.Join(p.Hosts, ","),
+		strings.Join(hostAddrStrs, ","),
+		strings.Join(portStrs, ","),
 		p.User,
 		passwordStr,
 		p.ConnectTimeout,
@@ -171,6 +217,7 @@ gsslib = %s
 		p.RequirePeer,
 		p.KrbSrvname,
 		p.GSSLib,
+		p.TargetSessionAttrs,
 	)
 }
 
@@ -221,6 +268,20 @@ func (p *postgresService) Set(
 			if validString {
 				field.SetString(v)
 			}
+		} else if strings.ToLower(k) == "targetsessionattrs" {
+			validString := false
+			for i := range TargetSessionAttrsModes {
+				if TargetSessionAttrsModes[i] == TargetSessionAttrsMode(v) {
+					validString = true
+					break
+				}
+			}
+
+			if !validString {
+				return fmt.Errorf("Invalid target_session_attrs value %s", v)
+			}
+
+			field.SetString(v)
 		} else {
 			field.SetString(v)
 		}
@@ -233,6 +294,44 @@ func (p *postgresService) Set(
 		} else {
 			field.SetBool(true)
 		}
+	} else if field.Kind() == reflect.Slice {
+		parts := strings.Split(v, ",")
+
+		switch field.Type().Elem().Kind() {
+		case reflect.String:
+			slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+			for i, part := range parts {
+				slice.Index(i).SetString(part)
+			}
+			field.Set(slice)
+		case reflect.Int:
+			slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+			for i, part := range parts {
+				intVal, err := strconv.Atoi(part)
+				if err != nil {
+					return err
+				}
+				slice.Index(i).SetInt(int64(intVal))
+			}
+			field.Set(slice)
+		default:
+			// net.IP ([]byte) is the one slice-of-slice field we support
+			// (HostAddrs), handled separately since its elements aren't a
+			// plain int/string.
+			if field.Type() != reflect.TypeOf([]net.IP{}) {
+				return fmt.Errorf("Field type %v is unhandled.", field.Type())
+			}
+
+			ips := make([]net.IP, len(parts))
+			for i, part := range parts {
+				ip := net.ParseIP(part)
+				if ip == nil {
+					return fmt.Errorf("Invalid IP address %s", part)
+				}
+				ips[i] = ip
+			}
+			field.Set(reflect.ValueOf(ips))
+		}
 	} else {
 		return fmt.Errorf("Field type %v is unhandled.", field.Kind())
 	}
@@ -288,15 +387,33 @@ func ParsePgServices(
 
 		pgServiceCategory := postgresService{}
 		for key, value := range section.KeysHash() {
-			structMember, err := pgServiceKeyToStructMember(key)
-			if err != nil {
-				return nil, err
+			structMember, memberErr := pgServiceKeyToStructMember(key)
+			if memberErr == nil {
+				if err := pgServiceCategory.Set(structMember, value); err != nil {
+					return nil, err
+				}
+				continue
 			}
 
-			err = pgServiceCategory.Set(structMember, value)
+			// key doesn't map onto a postgresService field, but it may
+			// still be a keyword someone registered via RegisterKeyword
+			// (eg. a newer PG keyword this struct doesn't have a field for
+			// yet). Fall back to the registry instead of rejecting it
+			// outright, so RegisterKeyword actually has an effect here.
+			kw, ok := LookupKeyword(key)
+			if !ok {
+				return nil, memberErr
+			}
+
+			coerced, err := coerceKeywordValue(kw, value)
 			if err != nil {
 				return nil, err
 			}
+
+			if pgServiceCategory.registryExtra == nil {
+				pgServiceCategory.registryExtra = make(map[string]any)
+			}
+			pgServiceCategory.registryExtra[kw.Name] = coerced
 		}
 
 		pgServices.addService(section.Name(), pgServiceCategory)
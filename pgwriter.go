@@ -0,0 +1,136 @@
+package pgservices
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+
+	ini "gopkg.in/ini.v1"
+)
+
+// WriteTo serializes p back out as a valid pg_service.conf: one INI section
+// per service, with only the keys that differ from their zero value
+// written out. Sections are emitted in alphabetical order so the output is
+// stable.
+func (p *PostgresServiceGroup) WriteTo(w io.Writer) (int64, error) {
+	cfg := ini.Empty()
+
+	names := make([]string, 0, len(p.Category))
+	for name := range p.Category {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		section, err := cfg.NewSection(name)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := writeServiceKeys(section, p.Category[name]); err != nil {
+			return 0, err
+		}
+	}
+
+	return cfg.WriteTo(w)
+}
+
+// writeServiceKeys walks svc's exported fields via reflection, skipping zero
+// values, and sets the corresponding ini key for everything else using the
+// reverse of pgServiceKeyToStructMember. It then does the same for
+// registryExtra, the unexported map backing keywords that don't have a
+// dedicated field (see ParsePgServices), using the registry to recover
+// their keyword name instead of pgServiceStructMemberToKey.
+func writeServiceKeys(section *ini.Section, svc postgresService) error {
+	structVal := reflect.ValueOf(svc)
+	structType := structVal.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" {
+			// unexported field (registryExtra); handled separately below.
+			continue
+		}
+
+		value := structVal.Field(i)
+		if value.IsZero() {
+			continue
+		}
+
+		key, err := pgServiceStructMemberToKey(field.Name)
+		if err != nil {
+			return err
+		}
+
+		section.Key(key).SetValue(serviceFieldToString(value))
+	}
+
+	for name, value := range svc.registryExtra {
+		kw, ok := LookupKeyword(name)
+		if !ok {
+			return fmt.Errorf("Unregistered keyword %s in registryExtra", name)
+		}
+
+		section.Key(kw.Name).SetValue(registryValueToString(value))
+	}
+
+	return nil
+}
+
+// registryValueToString renders a coerced registryExtra value (see
+// coerceKeywordValue) the way serviceFieldToString renders a struct field:
+// booleans as 0/1, everything else via its natural string form.
+func registryValueToString(value any) string {
+	if b, ok := value.(bool); ok {
+		if b {
+			return "1"
+		}
+		return "0"
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// serviceFieldToString renders a single postgresService field value the way
+// it should appear on the right-hand side of a pg_service.conf key=value
+// line: booleans as 0/1, slices comma-joined (net.IP elements via
+// net.IP.String, not a raw byte cast), everything else via its natural
+// string form.
+func serviceFieldToString(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.Bool:
+		if value.Bool() {
+			return "1"
+		}
+		return "0"
+	case reflect.Slice:
+		parts := make([]string, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			elem := value.Index(i)
+			if ip, ok := elem.Interface().(net.IP); ok {
+				parts[i] = ip.String()
+			} else {
+				parts[i] = fmt.Sprintf("%v", elem.Interface())
+			}
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprintf("%v", value.Interface())
+	}
+}
+
+// AddService adds svc to p under name. It's the exported counterpart to
+// addService, for callers building up a service file programmatically
+// (eg. before calling WriteTo) rather than via ParsePgServices.
+func (p *PostgresServiceGroup) AddService(name string, svc PostgresService) error {
+	return p.addService(name, svc)
+}
+
+// RemoveService deletes the service named name from p, if present. Removing
+// a service that doesn't exist is a no-op.
+func (p *PostgresServiceGroup) RemoveService(name string) {
+	delete(p.Category, name)
+}
@@ -0,0 +1,105 @@
+package pgservices
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+// TestWriteToRoundTrip checks that parsing a pg_service.conf, writing it
+// back out with WriteTo and parsing that output again yields the same
+// services.
+func TestWriteToRoundTrip(t *testing.T) {
+	buffer := `[service_one]
+host = db.example.com,db2.example.com
+port = 5432
+dbname = test_db
+user = dbuser
+password = abc123
+sslmode = require
+requiressl = 1
+keepalives = 1
+[service_two]
+host = localhost
+port = 5433
+dbname = other_db
+`
+
+	original, err := ParsePgServices(ioutil.NopCloser(bytes.NewReader([]byte(buffer))))
+	if err != nil {
+		t.Fatalf("Error parsing buffer: %v", err)
+	}
+
+	var out bytes.Buffer
+	if _, err := original.WriteTo(&out); err != nil {
+		t.Fatalf("Error writing services: %v", err)
+	}
+
+	reparsed, err := ParsePgServices(ioutil.NopCloser(bytes.NewReader(out.Bytes())))
+	if err != nil {
+		t.Fatalf("Error reparsing written output: %v\n%s", err, out.String())
+	}
+
+	if !reflect.DeepEqual(original.Category, reparsed.Category) {
+		t.Errorf("Round-tripped services don't match.\noriginal: %#v\nreparsed: %#v", original.Category, reparsed.Category)
+	}
+}
+
+// TestWriteToRoundTripRegistryExtra checks that a service whose file uses a
+// registered keyword with no dedicated postgresService field (see
+// registryExtra) round-trips through WriteTo instead of erroring out.
+func TestWriteToRoundTripRegistryExtra(t *testing.T) {
+	buffer := `[service_one]
+host = h1
+channel_binding = require
+sslpassword = hunter2
+`
+
+	original, err := ParsePgServices(ioutil.NopCloser(bytes.NewReader([]byte(buffer))))
+	if err != nil {
+		t.Fatalf("Error parsing buffer: %v", err)
+	}
+
+	var out bytes.Buffer
+	if _, err := original.WriteTo(&out); err != nil {
+		t.Fatalf("Error writing services: %v", err)
+	}
+
+	reparsed, err := ParsePgServices(ioutil.NopCloser(bytes.NewReader(out.Bytes())))
+	if err != nil {
+		t.Fatalf("Error reparsing written output: %v\n%s", err, out.String())
+	}
+
+	if !reflect.DeepEqual(original.Category, reparsed.Category) {
+		t.Errorf("Round-tripped services don't match.\noriginal: %#v\nreparsed: %#v", original.Category, reparsed.Category)
+	}
+
+	svc := reparsed.Category["service_one"]
+	if v, ok := svc.Extra("channel_binding"); !ok || v != "require" {
+		t.Errorf("Expected channel_binding=require to survive the round trip, got %v (ok=%v)", v, ok)
+	}
+}
+
+// TestAddRemoveService exercises the programmatic AddService/RemoveService
+// API added alongside WriteTo.
+func TestAddRemoveService(t *testing.T) {
+	group := New(nil)
+
+	svc := PostgresService{DBName: "test_db", Hosts: []string{"localhost"}}
+	if err := group.AddService("my_service", svc); err != nil {
+		t.Fatalf("Error adding service: %v", err)
+	}
+
+	if err := group.AddService("my_service", svc); err == nil {
+		t.Errorf("Expected an error adding a duplicate service name")
+	}
+
+	group.RemoveService("my_service")
+	if _, ok := group.Category["my_service"]; ok {
+		t.Errorf("Expected my_service to be removed")
+	}
+
+	// Removing something that was never there shouldn't blow up.
+	group.RemoveService("my_service")
+}
@@ -0,0 +1,26 @@
+// Package pgxconfig maps a parsed pg_service.conf entry onto pgx's
+// connection Config. It's kept separate from the main pgservices package so
+// that importing pgservices doesn't pull in pgx/pgconn for callers who just
+// want to parse and inspect service files.
+package pgxconfig
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgconn"
+
+	"github.com/dudefellah/pgservices"
+)
+
+// FromService builds a *pgconn.Config from a parsed pg_service.conf entry,
+// the way pgx itself would build one from environment variables and a
+// service file. RuntimeParams carries along the keywords pgconn doesn't have
+// dedicated fields for (application_name, client_encoding, options).
+func FromService(svc pgservices.PostgresService) (*pgconn.Config, error) {
+	cfg, err := pgconn.ParseConfig(svc.KeywordValueDSN())
+	if err != nil {
+		return nil, fmt.Errorf("pgxconfig: %w", err)
+	}
+
+	return cfg, nil
+}